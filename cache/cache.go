@@ -0,0 +1,137 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package cache persists fetched GitHub PRs to disk to avoid re-paying
+// GitHub's rate limit on every run.
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+)
+
+// ErrMiss is returned by Get when no cached entry exists for the key.
+var ErrMiss = errors.New("cache: miss")
+
+// Cache is a JSON-per-PR on-disk cache, rooted at a directory, keyed by
+// owner/repo#number.
+type Cache struct {
+	dir string
+}
+
+// New returns a Cache rooted at dir, creating it if necessary. An empty dir
+// defaults to $XDG_CACHE_HOME/devstats (or the OS user cache dir).
+func New(dir string) (*Cache, error) {
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(base, "devstats")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Cache{dir: dir}, nil
+}
+
+// Get returns the cached pull request for owner/repo#number. It returns
+// ErrMiss if no cached copy exists.
+func (c *Cache) Get(owner, repo string, number int) (*github.PullRequest, error) {
+	b, err := os.ReadFile(c.path(owner, repo, number))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pr github.PullRequest
+	if err := json.Unmarshal(b, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// Stale reports whether the cached copy of owner/repo#number is missing or
+// older than updatedAt, meaning it should be refetched.
+func (c *Cache) Stale(owner, repo string, number int, updatedAt time.Time) bool {
+	cached, err := c.Get(owner, repo, number)
+	if err != nil {
+		return true
+	}
+
+	return cached.GetUpdatedAt().Time.Before(updatedAt)
+}
+
+// Put upserts pr into the cache under owner/repo#number.
+func (c *Cache) Put(owner, repo string, pr *github.PullRequest) error {
+	b, err := json.Marshal(pr)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(owner, repo, pr.GetNumber())
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o644)
+}
+
+// List returns the PR numbers cached for owner/repo, so callers can
+// enumerate candidates without the GitHub search API (e.g. --offline).
+func (c *Cache) List(owner, repo string) ([]int, error) {
+	entries, err := os.ReadDir(filepath.Join(c.dir, owner, repo))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var numbers []int
+	for _, e := range entries {
+		n, err := strconv.Atoi(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		numbers = append(numbers, n)
+	}
+
+	return numbers, nil
+}
+
+func (c *Cache) path(owner, repo string, number int) string {
+	return filepath.Join(c.dir, owner, repo, fmt.Sprintf("%d.json", number))
+}
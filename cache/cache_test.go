@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+)
+
+func TestGetMiss(t *testing.T) {
+	c, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Get("jtsiros", "devstats", 1); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get on empty cache = %v, want ErrMiss", err)
+	}
+}
+
+func TestPutGetRoundTrip(t *testing.T) {
+	c, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr := &github.PullRequest{Number: github.Int(42)}
+	if err := c.Put("jtsiros", "devstats", pr); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.Get("jtsiros", "devstats", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetNumber() != 42 {
+		t.Errorf("Get(42).GetNumber() = %d, want 42", got.GetNumber())
+	}
+}
+
+func TestStale(t *testing.T) {
+	c, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	pr := &github.PullRequest{Number: github.Int(1), UpdatedAt: &github.Timestamp{Time: now}}
+	if err := c.Put("jtsiros", "devstats", pr); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Stale("jtsiros", "devstats", 1, now.Add(-time.Hour)) {
+		t.Error("Stale should be false when updatedAt is older than the cached copy")
+	}
+
+	if !c.Stale("jtsiros", "devstats", 1, now.Add(time.Hour)) {
+		t.Error("Stale should be true when updatedAt is newer than the cached copy")
+	}
+
+	if !c.Stale("jtsiros", "devstats", 2, now) {
+		t.Error("Stale should be true for an uncached PR")
+	}
+}
+
+func TestList(t *testing.T) {
+	c, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if numbers, err := c.List("jtsiros", "devstats"); err != nil || len(numbers) != 0 {
+		t.Fatalf("List on empty cache = %v, %v, want none", numbers, err)
+	}
+
+	for _, n := range []int{1, 2, 3} {
+		if err := c.Put("jtsiros", "devstats", &github.PullRequest{Number: github.Int(n)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	numbers, err := c.List("jtsiros", "devstats")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[int]bool{}
+	for _, n := range numbers {
+		seen[n] = true
+	}
+	for _, want := range []int{1, 2, 3} {
+		if !seen[want] {
+			t.Errorf("List() = %v, missing %d", numbers, want)
+		}
+	}
+}
@@ -0,0 +1,524 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+	"golang.org/x/sync/errgroup"
+)
+
+func init() {
+	rootCmd.AddCommand(activityCmd)
+
+	activityCmd.Flags().StringVarP(&activityOpts.Repo, "repo", "r", "",
+		"repository to generate the activity report for. ex: jtsiros/devstats")
+	_ = activityCmd.MarkFlagRequired("repo")
+
+	activityCmd.Flags().StringVarP(&activityOpts.FromDate, "from", "f",
+		time.Now().AddDate(0, -1, 0).Format("2006-01-02"),
+		"from date to start the activity report. Defaults to past 30 days",
+	)
+
+	activityCmd.Flags().StringVarP(&activityOpts.ToDate, "to", "t",
+		time.Now().Format("2006-01-02"),
+		"to date to end the activity report. Defaults to today",
+	)
+}
+
+// SearchActivityOpts configures the [from, to] window and repo the activity
+// report summarizes. --format picks the activityRenderer used to print the
+// resulting ActivityStats.
+type SearchActivityOpts struct {
+	Repo     string
+	FromDate string
+	ToDate   string
+}
+
+var activityOpts = SearchActivityOpts{}
+
+var activityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "Generates a full repo activity report for a given window.",
+	Long: `Generates a report covering:
+
+Pull requests: opened, merged, closed counts and top authors.
+Issues: opened, closed, and unresolved counts.
+Releases: number of releases published in the window (release authors are not counted).
+Code activity: per-author commit counts on the default branch.
+
+This mirrors the activity reports Gitea generates for a repository.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runActivity()
+	},
+	SilenceUsage: true,
+}
+
+// AuthorCommitCount is the per-author tally in ActivityStats.CodeActivity.
+type AuthorCommitCount struct {
+	Login   string
+	Name    string
+	Commits int
+}
+
+// AuthorPRCount is the per-author tally behind ActivityStats.TopPRAuthors.
+type AuthorPRCount struct {
+	Login string
+	PRs   int
+}
+
+// CodeActivity summarizes commit activity on the default branch.
+type CodeActivity struct {
+	AuthorCommits []AuthorCommitCount
+}
+
+// ActivityStats is the full activity report for a repo over a window,
+// modeled after Gitea's own ActivityStats.
+type ActivityStats struct {
+	OpenedPRs         int
+	MergedPRs         int
+	ClosedPRs         int
+	TopPRAuthors      []AuthorPRCount
+	OpenedIssues      int
+	ClosedIssues      int
+	UnresolvedIssues  int
+	PublishedReleases int
+	CodeActivity      CodeActivity
+}
+
+func runActivity() error {
+	ctx := context.Background()
+	t := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: viper.GetString("GITHUB_TOKEN")},
+	)
+	oc := oauth2.NewClient(ctx, t)
+	gc := github.NewClient(oc)
+
+	ownerAndRepo := strings.Split(activityOpts.Repo, "/")
+	if len(ownerAndRepo) != ownerRepoTokenLen {
+		return errRepoFmt
+	}
+
+	from, err := time.Parse("2006-01-02", activityOpts.FromDate)
+	if err != nil {
+		return err
+	}
+
+	to, err := time.Parse("2006-01-02", activityOpts.ToDate)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Groking activity for %s from [%s] to [%s]...\n",
+		activityOpts.Repo, activityOpts.FromDate, activityOpts.ToDate)
+
+	stats, err := fetchActivityStats(ctx, gc, ownerAndRepo[0], ownerAndRepo[1], from, to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(colorGreen, "finished")
+
+	return renderActivity(os.Stdout, stats)
+}
+
+func fetchActivityStats(ctx context.Context, gc *github.Client, owner, repo string, from, to time.Time) (ActivityStats, error) {
+	var stats ActivityStats
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		opened, merged, closed, topAuthors, err := countPRs(ctx, gc, owner, repo)
+		stats.OpenedPRs = opened
+		stats.MergedPRs = merged
+		stats.ClosedPRs = closed
+		stats.TopPRAuthors = topAuthors
+		return err
+	})
+
+	g.Go(func() error {
+		opened, closed, unresolved, err := countIssues(ctx, gc, owner, repo)
+		stats.OpenedIssues = opened
+		stats.ClosedIssues = closed
+		stats.UnresolvedIssues = unresolved
+		return err
+	})
+
+	g.Go(func() error {
+		n, err := countReleases(ctx, gc, owner, repo, from, to)
+		stats.PublishedReleases = n
+		return err
+	})
+
+	g.Go(func() error {
+		commits, err := authorCommitCounts(ctx, gc, owner, repo, from, to)
+		stats.CodeActivity.AuthorCommits = commits
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		return ActivityStats{}, err
+	}
+
+	return stats, nil
+}
+
+// searchAllIssues runs query against Search.Issues and paginates through
+// every page, returning the full item list rather than just the search
+// result's GetTotal count, so callers can tally authors in addition to
+// counting.
+func searchAllIssues(ctx context.Context, gc *github.Client, query string) ([]*github.Issue, error) {
+	opt := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+
+	var all []*github.Issue
+	for {
+		sr, resp, err := gc.Search.Issues(ctx, query, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, sr.Issues...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return all, nil
+}
+
+func countPRs(ctx context.Context, gc *github.Client, owner, repo string) (opened, merged, closed int, topAuthors []AuthorPRCount, err error) {
+	openedQuery := fmt.Sprintf("is:pull-request repo:%s/%s created:%s..%s",
+		owner, repo, activityOpts.FromDate, activityOpts.ToDate)
+	mergedQuery := fmt.Sprintf("is:pull-request is:merged repo:%s/%s merged:%s..%s",
+		owner, repo, activityOpts.FromDate, activityOpts.ToDate)
+	closedQuery := fmt.Sprintf("is:pull-request is:closed is:unmerged repo:%s/%s closed:%s..%s",
+		owner, repo, activityOpts.FromDate, activityOpts.ToDate)
+
+	openedIssues, err := searchAllIssues(ctx, gc, openedQuery)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+
+	mergedIssues, err := searchAllIssues(ctx, gc, mergedQuery)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+
+	closedIssues, err := searchAllIssues(ctx, gc, closedQuery)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+
+	return len(openedIssues), len(mergedIssues), len(closedIssues), topPRAuthors(openedIssues), nil
+}
+
+// topPRAuthors tallies PR authors by login, sorted by PR count descending.
+func topPRAuthors(issues []*github.Issue) []AuthorPRCount {
+	counts := map[string]int{}
+	for _, i := range issues {
+		counts[i.GetUser().GetLogin()]++
+	}
+
+	top := make([]AuthorPRCount, 0, len(counts))
+	for login, n := range counts {
+		top = append(top, AuthorPRCount{Login: login, PRs: n})
+	}
+
+	sort.Slice(top, func(i, j int) bool { return top[i].PRs > top[j].PRs })
+
+	return top
+}
+
+func countIssues(ctx context.Context, gc *github.Client, owner, repo string) (opened, closed, unresolved int, err error) {
+	openedQuery := fmt.Sprintf("is:issue repo:%s/%s created:%s..%s",
+		owner, repo, activityOpts.FromDate, activityOpts.ToDate)
+	closedQuery := fmt.Sprintf("is:issue is:closed repo:%s/%s closed:%s..%s",
+		owner, repo, activityOpts.FromDate, activityOpts.ToDate)
+	unresolvedQuery := fmt.Sprintf("is:issue is:open repo:%s/%s", owner, repo)
+
+	o, _, err := gc.Search.Issues(ctx, openedQuery, &github.SearchOptions{})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	c, _, err := gc.Search.Issues(ctx, closedQuery, &github.SearchOptions{})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	u, _, err := gc.Search.Issues(ctx, unresolvedQuery, &github.SearchOptions{})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return o.GetTotal(), c.GetTotal(), u.GetTotal(), nil
+}
+
+func countReleases(ctx context.Context, gc *github.Client, owner, repo string, from, to time.Time) (int, error) {
+	opt := &github.ListOptions{PerPage: 100}
+
+	var count int
+	for {
+		releases, resp, err := gc.Repositories.ListReleases(ctx, owner, repo, opt)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, r := range releases {
+			if r.GetPublishedAt().IsZero() {
+				continue
+			}
+
+			if r.GetPublishedAt().Before(from) || r.GetPublishedAt().After(to) {
+				continue
+			}
+
+			count++
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return count, nil
+}
+
+func authorCommitCounts(ctx context.Context, gc *github.Client, owner, repo string, from, to time.Time) ([]AuthorCommitCount, error) {
+	opt := &github.CommitsListOptions{
+		Since:       from,
+		Until:       to,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	counts := map[string]*AuthorCommitCount{}
+
+	for {
+		commits, resp, err := gc.Repositories.ListCommits(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range commits {
+			login := c.GetAuthor().GetLogin()
+			name := c.GetCommit().GetAuthor().GetName()
+
+			key := login
+			if key == "" {
+				key = name
+			}
+
+			if counts[key] == nil {
+				counts[key] = &AuthorCommitCount{Login: login, Name: name}
+			}
+			counts[key].Commits++
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	authorCommits := make([]AuthorCommitCount, 0, len(counts))
+	for _, c := range counts {
+		authorCommits = append(authorCommits, *c)
+	}
+
+	sort.Slice(authorCommits, func(i, j int) bool {
+		return authorCommits[i].Commits > authorCommits[j].Commits
+	})
+
+	return authorCommits, nil
+}
+
+// activityRenderer formats an ActivityStats for display or machine
+// consumption. table, json, csv, and markdown all satisfy this so
+// renderActivity can dispatch on the --format flag the same way render does
+// for []ContributorStats in renderer.go.
+type activityRenderer interface {
+	Render(w io.Writer, stats ActivityStats) error
+}
+
+// activityRenderers is the format-name-to-activityRenderer registry
+// consulted by renderActivity.
+var activityRenderers = map[string]activityRenderer{
+	"table":    activityTableRenderer{},
+	"json":     activityJSONRenderer{},
+	"csv":      activityCSVRenderer{},
+	"markdown": activityMarkdownRenderer{},
+}
+
+// renderActivity dispatches to the activityRenderer registered for
+// outputFormat, falling back to the table renderer for an unrecognized
+// value.
+func renderActivity(w io.Writer, stats ActivityStats) error {
+	r, ok := activityRenderers[outputFormat]
+	if !ok {
+		r = activityTableRenderer{}
+	}
+
+	return r.Render(w, stats)
+}
+
+type activityTableRenderer struct{}
+
+func (activityTableRenderer) Render(w io.Writer, stats ActivityStats) error {
+	summary := table.NewWriter()
+	summary.SetOutputMirror(w)
+	summary.AppendHeader(table.Row{"Metric", "Count"})
+	summary.AppendRow(table.Row{"Opened PRs", stats.OpenedPRs})
+	summary.AppendRow(table.Row{"Merged PRs", stats.MergedPRs})
+	summary.AppendRow(table.Row{"Closed PRs", stats.ClosedPRs})
+	summary.AppendRow(table.Row{"Opened Issues", stats.OpenedIssues})
+	summary.AppendRow(table.Row{"Closed Issues", stats.ClosedIssues})
+	summary.AppendRow(table.Row{"Unresolved Issues", stats.UnresolvedIssues})
+	summary.AppendRow(table.Row{"Published Releases", stats.PublishedReleases})
+	summary.SetStyle(table.StyleColoredBlackOnGreenWhite)
+	summary.Render()
+
+	topAuthors := table.NewWriter()
+	topAuthors.SetOutputMirror(w)
+	topAuthors.AppendHeader(table.Row{"Login", "PRs"})
+	for _, a := range stats.TopPRAuthors {
+		topAuthors.AppendRow(table.Row{a.Login, a.PRs})
+	}
+	topAuthors.SetStyle(table.StyleColoredBlackOnGreenWhite)
+	topAuthors.Render()
+
+	commits := table.NewWriter()
+	commits.SetOutputMirror(w)
+	commits.AppendHeader(table.Row{"Login", "Name", "Commits"})
+	for _, a := range stats.CodeActivity.AuthorCommits {
+		commits.AppendRow(table.Row{a.Login, a.Name, a.Commits})
+	}
+	commits.SetStyle(table.StyleColoredBlackOnGreenWhite)
+	commits.Render()
+
+	return nil
+}
+
+type activityJSONRenderer struct{}
+
+func (activityJSONRenderer) Render(w io.Writer, stats ActivityStats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(stats)
+}
+
+type activityCSVRenderer struct{}
+
+func (activityCSVRenderer) Render(w io.Writer, stats ActivityStats) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	header := []string{
+		"opened_prs", "merged_prs", "closed_prs",
+		"opened_issues", "closed_issues", "unresolved_issues",
+		"published_releases",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	row := []string{
+		strconv.Itoa(stats.OpenedPRs), strconv.Itoa(stats.MergedPRs), strconv.Itoa(stats.ClosedPRs),
+		strconv.Itoa(stats.OpenedIssues), strconv.Itoa(stats.ClosedIssues), strconv.Itoa(stats.UnresolvedIssues),
+		strconv.Itoa(stats.PublishedReleases),
+	}
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+
+	if err := cw.Write(nil); err != nil {
+		return err
+	}
+	if err := cw.Write([]string{"login", "prs"}); err != nil {
+		return err
+	}
+	for _, a := range stats.TopPRAuthors {
+		if err := cw.Write([]string{a.Login, strconv.Itoa(a.PRs)}); err != nil {
+			return err
+		}
+	}
+
+	if err := cw.Write(nil); err != nil {
+		return err
+	}
+	if err := cw.Write([]string{"login", "name", "commits"}); err != nil {
+		return err
+	}
+	for _, a := range stats.CodeActivity.AuthorCommits {
+		if err := cw.Write([]string{a.Login, a.Name, strconv.Itoa(a.Commits)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type activityMarkdownRenderer struct{}
+
+func (activityMarkdownRenderer) Render(w io.Writer, stats ActivityStats) error {
+	fmt.Fprintln(w, "| Metric | Count |")
+	fmt.Fprintln(w, "|---|---|")
+	fmt.Fprintf(w, "| Opened PRs | %d |\n", stats.OpenedPRs)
+	fmt.Fprintf(w, "| Merged PRs | %d |\n", stats.MergedPRs)
+	fmt.Fprintf(w, "| Closed PRs | %d |\n", stats.ClosedPRs)
+	fmt.Fprintf(w, "| Opened Issues | %d |\n", stats.OpenedIssues)
+	fmt.Fprintf(w, "| Closed Issues | %d |\n", stats.ClosedIssues)
+	fmt.Fprintf(w, "| Unresolved Issues | %d |\n", stats.UnresolvedIssues)
+	fmt.Fprintf(w, "| Published Releases | %d |\n", stats.PublishedReleases)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Login | PRs |")
+	fmt.Fprintln(w, "|---|---|")
+	for _, a := range stats.TopPRAuthors {
+		fmt.Fprintf(w, "| %s | %d |\n", a.Login, a.PRs)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Login | Name | Commits |")
+	fmt.Fprintln(w, "|---|---|---|")
+	for _, a := range stats.CodeActivity.AuthorCommits {
+		fmt.Fprintf(w, "| %s | %s | %d |\n", a.Login, a.Name, a.Commits)
+	}
+
+	return nil
+}
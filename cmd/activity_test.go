@@ -0,0 +1,216 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+)
+
+// newTestGithubClient returns a go-github client whose BaseURL points at srv,
+// the same trick used to exercise the Gerrit client against an httptest
+// server in gerrit_test.go.
+func newTestGithubClient(t *testing.T, srv *httptest.Server) *github.Client {
+	t.Helper()
+
+	gc := github.NewClient(nil)
+	base, err := url.Parse(srv.URL + "/")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+	gc.BaseURL = base
+
+	return gc
+}
+
+func TestTopPRAuthors(t *testing.T) {
+	issues := []*github.Issue{
+		{User: &github.User{Login: github.String("alice")}},
+		{User: &github.User{Login: github.String("bob")}},
+		{User: &github.User{Login: github.String("alice")}},
+		{User: &github.User{Login: github.String("alice")}},
+	}
+
+	top := topPRAuthors(issues)
+
+	if len(top) != 2 {
+		t.Fatalf("topPRAuthors returned %d authors, want 2", len(top))
+	}
+	if top[0].Login != "alice" || top[0].PRs != 3 {
+		t.Errorf("top[0] = %+v, want alice with 3 PRs (sorted descending)", top[0])
+	}
+	if top[1].Login != "bob" || top[1].PRs != 1 {
+		t.Errorf("top[1] = %+v, want bob with 1 PR", top[1])
+	}
+}
+
+func TestAuthorCommitCountsFiltersByWindowAndPaginates(t *testing.T) {
+	pages := []string{
+		`[{"author": {"login": "alice"}, "commit": {"author": {"name": "Alice A"}}}]`,
+		`[{"author": {"login": "alice"}, "commit": {"author": {"name": "Alice A"}}},
+		  {"commit": {"author": {"name": "Bob B"}}}]`,
+	}
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if got := r.URL.Query().Get("since"); got != "2021-01-01T00:00:00Z" {
+			t.Errorf("request %d since = %q, want 2021-01-01T00:00:00Z", requests, got)
+		}
+		if got := r.URL.Query().Get("until"); got != "2021-02-01T00:00:00Z" {
+			t.Errorf("request %d until = %q, want 2021-02-01T00:00:00Z", requests, got)
+		}
+		if requests == 1 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+		}
+		fmt.Fprint(w, pages[requests-1])
+	}))
+	defer srv.Close()
+
+	gc := newTestGithubClient(t, srv)
+
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	counts, err := authorCommitCounts(context.Background(), gc, "jtsiros", "devstats", from, to)
+	if err != nil {
+		t.Fatalf("authorCommitCounts: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("made %d requests, want 2 (should follow pagination)", requests)
+	}
+
+	byKey := map[string]AuthorCommitCount{}
+	for _, c := range counts {
+		key := c.Login
+		if key == "" {
+			key = c.Name
+		}
+		byKey[key] = c
+	}
+
+	if got := byKey["alice"]; got.Commits != 2 {
+		t.Errorf("alice commits = %d, want 2 (one per page)", got.Commits)
+	}
+	if got := byKey["Bob B"]; got.Commits != 1 {
+		t.Errorf("Bob B commits = %d, want 1 (falls back to commit author name when login is empty)", got.Commits)
+	}
+}
+
+func TestCountReleasesFiltersByWindow(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"published_at": "2021-01-15T00:00:00Z"},
+			{"published_at": "2021-03-01T00:00:00Z"},
+			{}
+		]`)
+	}))
+	defer srv.Close()
+
+	gc := newTestGithubClient(t, srv)
+
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	n, err := countReleases(context.Background(), gc, "jtsiros", "devstats", from, to)
+	if err != nil {
+		t.Fatalf("countReleases: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("countReleases = %d, want 1 (outside-window and unpublished releases excluded)", n)
+	}
+}
+
+func TestRenderActivityJSON(t *testing.T) {
+	var buf bytes.Buffer
+	stats := ActivityStats{OpenedPRs: 3, TopPRAuthors: []AuthorPRCount{{Login: "alice", PRs: 2}}}
+
+	if err := (activityJSONRenderer{}).Render(&buf, stats); err != nil {
+		t.Fatalf("activityJSONRenderer.Render: %v", err)
+	}
+
+	var got ActivityStats
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.OpenedPRs != 3 || len(got.TopPRAuthors) != 1 {
+		t.Errorf("got = %+v, want OpenedPRs=3 and 1 top author", got)
+	}
+}
+
+func TestRenderActivityCSV(t *testing.T) {
+	var buf bytes.Buffer
+	stats := ActivityStats{
+		OpenedPRs:    3,
+		MergedPRs:    1,
+		TopPRAuthors: []AuthorPRCount{{Login: "alice", PRs: 2}},
+		CodeActivity: CodeActivity{AuthorCommits: []AuthorCommitCount{{Login: "bob", Name: "Bob B", Commits: 5}}},
+	}
+
+	if err := (activityCSVRenderer{}).Render(&buf, stats); err != nil {
+		t.Fatalf("activityCSVRenderer.Render: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("got %d lines, want 8 (summary header+row, blank, authors header+row, blank, commits header+row)", len(lines))
+	}
+	if !strings.Contains(lines[1], "3") || !strings.Contains(lines[1], "1") {
+		t.Errorf("summary row = %q, want opened/merged counts", lines[1])
+	}
+	if lines[4] != "alice,2" {
+		t.Errorf("top authors row = %q, want alice,2", lines[4])
+	}
+	if lines[7] != "bob,Bob B,5" {
+		t.Errorf("author commits row = %q, want bob,Bob B,5 (should not be dropped)", lines[7])
+	}
+}
+
+func TestRenderActivityMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	stats := ActivityStats{
+		TopPRAuthors: []AuthorPRCount{{Login: "alice", PRs: 2}},
+		CodeActivity: CodeActivity{AuthorCommits: []AuthorCommitCount{{Login: "bob", Name: "Bob B", Commits: 5}}},
+	}
+
+	if err := (activityMarkdownRenderer{}).Render(&buf, stats); err != nil {
+		t.Fatalf("activityMarkdownRenderer.Render: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "| alice | 2 |") {
+		t.Errorf("markdown output missing top author row:\n%s", out)
+	}
+	if !strings.Contains(out, "| bob | Bob B | 5 |") {
+		t.Errorf("markdown output missing code activity row:\n%s", out)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-gerrit"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(gerritCmd)
+
+	gerritCmd.Flags().StringVar(&gerritOpts.Host, "host", "go-review.googlesource.com",
+		"Gerrit host to fetch CLs from")
+
+	gerritCmd.Flags().StringArrayVar(&gerritOpts.Projects, "project", []string{},
+		"Gerrit project(s) to restrict the search to (repeatable)")
+
+	gerritCmd.Flags().StringArrayVarP(&gerritOpts.Authors, "authors", "a", []string{},
+		"authors (CL owners) to calculate CL stats for; CLs they only reviewed are not counted")
+
+	gerritCmd.Flags().StringVarP(&gerritOpts.FromDate, "from", "f",
+		time.Now().AddDate(0, -1, 0).Format("2006-01-02"),
+		"from date to generate CL stats. Defaults to past 30 days",
+	)
+}
+
+// SearchGerritOpts mirrors SearchPROpts for Gerrit-hosted projects.
+type SearchGerritOpts struct {
+	Host     string
+	Projects []string
+	Authors  []string
+	FromDate string
+}
+
+var gerritOpts = SearchGerritOpts{}
+
+var gerritCmd = &cobra.Command{
+	Use:   "gerrit",
+	Short: "Calculates contributor statistics for all CLs contributed by an author(s) on a Gerrit host.",
+	Long: `Calculates mean/median/median absolute deviation for the following:
+
+Merge time: how long it takes for a CL to be submitted.
+Patchsets: number of patch sets per CL.
+Comments: number of comments per CL.
+Change size (+/-) : total number of line changes per CL.
+
+Sum:
+CLs: total number of CLs merged by from date.
+
+This is the Gerrit analogue of the pr command, useful for analyzing
+Gerrit-hosted projects such as Go or Chromium.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGerrit()
+	},
+	SilenceUsage: true,
+}
+
+// gerritSource implements Source against a Gerrit host via the
+// andygrunwald/go-gerrit REST client, making gerrit a second
+// implementation of the Source interface alongside githubSource.
+type gerritSource struct {
+	client   *gerrit.Client
+	projects []string
+}
+
+// gerritPageSize is the number of changes requested per QueryChanges call.
+// Gerrit reports whether more results exist past this page via the last
+// change's MoreChanges field rather than a total count.
+const gerritPageSize = 100
+
+func (s *gerritSource) FetchMerged(ctx context.Context, author string, since time.Time) ([]Contribution, error) {
+	query := searchByGerritAuthor(author, s.projects, since)
+
+	var contributions []Contribution
+	start := 0
+	for {
+		cls, _, err := s.client.Changes.QueryChanges(ctx, &gerrit.QueryChangeOptions{
+			QueryOptions: gerrit.QueryOptions{
+				Query: []string{query},
+				Limit: gerritPageSize,
+				Start: start,
+			},
+			ChangeOptions: gerrit.ChangeOptions{
+				AdditionalFields: []string{"CURRENT_REVISION", "DETAILED_ACCOUNTS", "MESSAGES"},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cl := range *cls {
+			if cl.Status != "MERGED" || cl.Submitted == nil {
+				continue
+			}
+
+			rev := cl.Revisions[cl.CurrentRevision]
+
+			contributions = append(contributions, Contribution{
+				CreatedAt: cl.Created.Time,
+				MergedAt:  cl.Submitted.Time,
+				Additions: cl.Insertions,
+				Deletions: -cl.Deletions,
+				Comments:  len(cl.Messages),
+				Patchsets: rev.Number,
+			})
+		}
+
+		if len(*cls) == 0 || !(*cls)[len(*cls)-1].MoreChanges {
+			break
+		}
+		start += len(*cls)
+	}
+
+	return contributions, nil
+}
+
+func runGerrit() error {
+	ctx := context.Background()
+	client, err := gerrit.NewClient(ctx, fmt.Sprintf("https://%s", gerritOpts.Host), nil)
+	if err != nil {
+		return err
+	}
+
+	src := &gerritSource{client: client, projects: gerritOpts.Projects}
+
+	since, err := time.Parse("2006-01-02", gerritOpts.FromDate)
+	if err != nil {
+		return err
+	}
+
+	var cstats []ContributorStats
+	fmt.Printf("Groking CL stats for %s from [%s]...\n", gerritOpts.Authors, gerritOpts.FromDate)
+
+	for _, a := range gerritOpts.Authors {
+		contributions, err := src.FetchMerged(ctx, a, since)
+		if err != nil {
+			return err
+		}
+
+		s := calculateStatsFromContributions(contributions, 0.0)
+		s.Author = a
+		cstats = append(cstats, s)
+	}
+
+	fmt.Println(colorGreen, "finished")
+	render(Meta{Repo: gerritHost(), FromDate: gerritOpts.FromDate}, cstats)
+
+	return nil
+}
+
+// gerritHost describes the host/project scope of a gerrit run for Meta,
+// since gerrit has no single "owner/repo" the way GitHub does.
+func gerritHost() string {
+	if len(gerritOpts.Projects) == 0 {
+		return gerritOpts.Host
+	}
+
+	return fmt.Sprintf("%s (%s)", gerritOpts.Host, strings.Join(gerritOpts.Projects, ","))
+}
+
+// searchByGerritAuthor builds a Gerrit change-search query equivalent to
+// searchByAuthor's GitHub search query. Gerrit ANDs bare terms together, and
+// a CL belongs to exactly one project, so 2+ --project values are wrapped in
+// an OR group rather than appended as separate terms.
+func searchByGerritAuthor(author string, projects []string, since time.Time) string {
+	q := fmt.Sprintf("status:merged owner:%s after:%s", author, since.Format("2006-01-02"))
+
+	switch len(projects) {
+	case 0:
+	case 1:
+		q += fmt.Sprintf(" project:%s", projects[0])
+	default:
+		terms := make([]string, len(projects))
+		for i, p := range projects {
+			terms[i] = fmt.Sprintf("project:%s", p)
+		}
+		q += fmt.Sprintf(" (%s)", strings.Join(terms, " OR "))
+	}
+
+	return q
+}
@@ -0,0 +1,198 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-gerrit"
+)
+
+// gerritChangesResponse is a minimal )]}'-prefixed QueryChanges payload: one
+// merged CL with known Insertions/Deletions so FetchMerged's ChangeSize math
+// can be checked end to end, and one non-merged CL that must be filtered out.
+// deletions is negative, matching Gerrit's actual wire format.
+const gerritChangesResponse = `)]}'
+[
+  {
+    "status": "MERGED",
+    "created": "2021-01-01 00:00:00.000000000",
+    "submitted": "2021-01-02 00:00:00.000000000",
+    "insertions": 10,
+    "deletions": -4,
+    "current_revision": "rev1",
+    "revisions": {
+      "rev1": {"_number": 3}
+    },
+    "messages": [{"id": "m1"}, {"id": "m2"}]
+  },
+  {
+    "status": "NEW",
+    "created": "2021-01-01 00:00:00.000000000",
+    "insertions": 1,
+    "deletions": 1,
+    "current_revision": "rev1",
+    "revisions": {
+      "rev1": {"_number": 1}
+    }
+  }
+]`
+
+func TestGerritSourceFetchMerged(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, gerritChangesResponse)
+	}))
+	defer srv.Close()
+
+	client, err := gerrit.NewClient(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatalf("gerrit.NewClient: %v", err)
+	}
+
+	src := &gerritSource{client: client, projects: []string{"devstats"}}
+
+	contributions, err := src.FetchMerged(context.Background(), "jdoe", time.Now())
+	if err != nil {
+		t.Fatalf("FetchMerged: %v", err)
+	}
+
+	if len(contributions) != 1 {
+		t.Fatalf("FetchMerged returned %d contributions, want 1 (non-merged CL should be filtered)", len(contributions))
+	}
+
+	c := contributions[0]
+	if c.Additions != 10 {
+		t.Errorf("Additions = %d, want 10", c.Additions)
+	}
+	if c.Deletions != 4 {
+		t.Errorf("Deletions = %d, want 4 (positive, not Gerrit's negative wire value)", c.Deletions)
+	}
+	if c.Comments != 2 {
+		t.Errorf("Comments = %d, want 2", c.Comments)
+	}
+	if c.Patchsets != 3 {
+		t.Errorf("Patchsets = %d, want 3", c.Patchsets)
+	}
+
+	s := calculateStatsFromContributions(contributions, 0.0)
+	if s.ChangeSize.Mean != 14 {
+		t.Errorf("ChangeSize.Mean = %v, want 14 (insertions+deletions, not a net diff)", s.ChangeSize.Mean)
+	}
+}
+
+func TestGerritSourceFetchMergedPaginates(t *testing.T) {
+	pages := []string{
+		`)]}'
+[
+  {
+    "status": "MERGED",
+    "created": "2021-01-01 00:00:00.000000000",
+    "submitted": "2021-01-02 00:00:00.000000000",
+    "insertions": 1,
+    "deletions": 0,
+    "current_revision": "rev1",
+    "revisions": {"rev1": {"_number": 1}},
+    "messages": [],
+    "_more_changes": true
+  }
+]`,
+		`)]}'
+[
+  {
+    "status": "MERGED",
+    "created": "2021-01-03 00:00:00.000000000",
+    "submitted": "2021-01-04 00:00:00.000000000",
+    "insertions": 2,
+    "deletions": 0,
+    "current_revision": "rev1",
+    "revisions": {"rev1": {"_number": 1}},
+    "messages": []
+  }
+]`,
+	}
+
+	var starts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		starts = append(starts, r.URL.Query().Get("start"))
+		fmt.Fprint(w, pages[len(starts)-1])
+	}))
+	defer srv.Close()
+
+	client, err := gerrit.NewClient(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatalf("gerrit.NewClient: %v", err)
+	}
+
+	src := &gerritSource{client: client}
+
+	contributions, err := src.FetchMerged(context.Background(), "jdoe", time.Now())
+	if err != nil {
+		t.Fatalf("FetchMerged: %v", err)
+	}
+
+	if len(contributions) != 2 {
+		t.Fatalf("FetchMerged returned %d contributions, want 2 (one per page)", len(contributions))
+	}
+	if want := []string{"", "1"}; !reflect.DeepEqual(starts, want) {
+		t.Errorf("requested start values = %v, want %v (should resume after the last change on MoreChanges)", starts, want)
+	}
+}
+
+func TestSearchByGerritAuthor(t *testing.T) {
+	since := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		projects []string
+		want     string
+	}{
+		{
+			name:     "no projects",
+			projects: nil,
+			want:     "status:merged owner:jdoe after:2021-01-01",
+		},
+		{
+			name:     "one project",
+			projects: []string{"go"},
+			want:     "status:merged owner:jdoe after:2021-01-01 project:go",
+		},
+		{
+			name:     "multiple projects are OR-ed",
+			projects: []string{"go", "tools"},
+			want:     "status:merged owner:jdoe after:2021-01-01 (project:go OR project:tools)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchByGerritAuthor("jdoe", tt.projects, since); got != tt.want {
+				t.Errorf("searchByGerritAuthor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
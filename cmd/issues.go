@@ -0,0 +1,401 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+	"golang.org/x/sync/errgroup"
+)
+
+func init() {
+	rootCmd.AddCommand(issuesCmd)
+
+	issuesCmd.Flags().StringVarP(&issuesOpts.Repo, "repo", "r", "",
+		"repository to fetch issues from. ex: jtsiros/devstats")
+	_ = issuesCmd.MarkFlagRequired("repo")
+
+	issuesCmd.Flags().StringVarP(&issuesOpts.Author, "author", "a", "",
+		"restrict to issues created by this author")
+
+	issuesCmd.Flags().StringVarP(&issuesOpts.Label, "label", "l", "",
+		"restrict to issues with this label")
+
+	issuesCmd.Flags().StringVarP(&issuesOpts.FromDate, "from", "f",
+		time.Now().AddDate(0, -1, 0).Format("2006-01-02"),
+		"from date to generate issue stats. Defaults to past 30 days",
+	)
+}
+
+// SearchIssuesOpts configures the issuesCmd search query (repo, author,
+// label, and from-date). --format picks the issueStatsRenderer used to
+// print the resulting []LabelStats.
+type SearchIssuesOpts struct {
+	Repo     string
+	Author   string
+	Label    string
+	FromDate string
+}
+
+var issuesOpts = SearchIssuesOpts{}
+
+var issuesCmd = &cobra.Command{
+	Use:   "issues",
+	Short: "Calculates issue close-time and first-response statistics.",
+	Long: `Calculates mean/median/median absolute deviation for the following,
+broken down per label:
+
+Close time: how long it takes for an issue to be closed.
+First response: how long it takes for someone other than the author to
+comment on an issue.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runIssues()
+	},
+	SilenceUsage: true,
+}
+
+// LabelStats is the close-time/first-response breakdown for a single label
+// ("" meaning unlabeled issues are reported together).
+type LabelStats struct {
+	Label         string
+	Issues        int
+	CloseTime     Statistics
+	FirstResponse Statistics
+}
+
+func runIssues() error {
+	ctx := context.Background()
+	t := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: viper.GetString("GITHUB_TOKEN")},
+	)
+	oc := oauth2.NewClient(ctx, t)
+	gc := github.NewClient(oc)
+
+	fmt.Printf("Groking issue stats for %s from [%s]...\n", issuesOpts.Repo, issuesOpts.FromDate)
+
+	issues, err := closedIssues(ctx, gc, issuesOpts.Repo)
+	if err != nil {
+		return err
+	}
+
+	byLabel, err := labelStats(ctx, gc, issuesOpts.Repo, issues)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(colorGreen, "finished")
+
+	return renderIssueStats(os.Stdout, byLabel)
+}
+
+// closedIssues fetches closed issues matching --repo/--author/--label/--from.
+func closedIssues(ctx context.Context, gc *github.Client, repo string) ([]*github.Issue, error) {
+	opt := &github.SearchOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	q := searchClosedIssues(repo)
+	fmt.Println("search query: [", q, "]")
+
+	var all []*github.Issue
+	for {
+		sr, resp, err := gc.Search.Issues(ctx, q, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range sr.Issues {
+			if i.IsPullRequest() {
+				continue
+			}
+			all = append(all, i)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return all, nil
+}
+
+func searchClosedIssues(repo string) string {
+	q := fmt.Sprintf("is:issue is:closed repo:%s created:>%s", repo, issuesOpts.FromDate)
+
+	if issuesOpts.Author != "" {
+		q += fmt.Sprintf(" author:%s", issuesOpts.Author)
+	}
+
+	if issuesOpts.Label != "" {
+		q += fmt.Sprintf(" label:%s", issuesOpts.Label)
+	}
+
+	return q
+}
+
+// labelStats fans out over issues to fetch first-response comments, then
+// reduces into one LabelStats per label.
+func labelStats(ctx context.Context, gc *github.Client, repo string, issues []*github.Issue) ([]LabelStats, error) {
+	ownerAndRepo := strings.Split(repo, "/")
+	if len(ownerAndRepo) != ownerRepoTokenLen {
+		return nil, errRepoFmt
+	}
+
+	type sample struct {
+		label         string
+		closeHours    float64
+		responseHours float64
+		hasResponse   bool
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	samples := make(chan sample, workerChanSize)
+	work := make(chan *github.Issue, workerChanSize)
+
+	g.Go(func() error {
+		defer close(work)
+		for _, i := range issues {
+			work <- i
+		}
+		return nil
+	})
+
+	workers := int32(nWorkers)
+	for w := 0; w < nWorkers; w++ {
+		g.Go(func() error {
+			defer func() {
+				if atomic.AddInt32(&workers, -1) == 0 {
+					close(samples)
+				}
+			}()
+
+			for i := range work {
+				closeHours := i.GetClosedAt().Sub(i.GetCreatedAt().Time).Hours()
+
+				respHours, hasResponse, err := firstResponseHours(ctx, gc, ownerAndRepo[0], ownerAndRepo[1], i)
+				if err != nil {
+					return err
+				}
+
+				labels := i.Labels
+				if len(labels) == 0 {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case samples <- sample{closeHours: closeHours, responseHours: respHours, hasResponse: hasResponse}:
+					}
+					continue
+				}
+
+				for _, l := range labels {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case samples <- sample{label: l.GetName(), closeHours: closeHours, responseHours: respHours, hasResponse: hasResponse}:
+					}
+				}
+			}
+
+			return nil
+		})
+	}
+
+	closeTimes := map[string][]float64{}
+	responseTimes := map[string][]float64{}
+	counts := map[string]int{}
+
+	for s := range samples {
+		closeTimes[s.label] = append(closeTimes[s.label], s.closeHours)
+		counts[s.label]++
+		if s.hasResponse {
+			responseTimes[s.label] = append(responseTimes[s.label], s.responseHours)
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var stats []LabelStats
+	for label, n := range counts {
+		stats = append(stats, LabelStats{
+			Label:         label,
+			Issues:        n,
+			CloseTime:     calcStats(closeTimes[label]),
+			FirstResponse: calcStats(responseTimes[label]),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Label < stats[j].Label })
+
+	return stats, nil
+}
+
+// firstResponseHours returns the hours between issue creation and the first
+// comment by someone other than the issue's author.
+func firstResponseHours(ctx context.Context, gc *github.Client, owner, repo string, issue *github.Issue) (float64, bool, error) {
+	comments, _, err := gc.Issues.ListComments(ctx, owner, repo, issue.GetNumber(), nil)
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, c := range comments {
+		if c.GetUser().GetLogin() == issue.GetUser().GetLogin() {
+			continue
+		}
+
+		return c.GetCreatedAt().Sub(issue.GetCreatedAt().Time).Hours(), true, nil
+	}
+
+	return 0, false, nil
+}
+
+// issueStatsRenderer formats []LabelStats for display or machine
+// consumption. table, json, csv, and markdown all satisfy this so
+// renderIssueStats can dispatch on the --format flag the same way render
+// does for []ContributorStats in renderer.go.
+type issueStatsRenderer interface {
+	Render(w io.Writer, stats []LabelStats) error
+}
+
+// issueStatsRenderers is the format-name-to-issueStatsRenderer registry
+// consulted by renderIssueStats.
+var issueStatsRenderers = map[string]issueStatsRenderer{
+	"table":    issueStatsTableRenderer{},
+	"json":     issueStatsJSONRenderer{},
+	"csv":      issueStatsCSVRenderer{},
+	"markdown": issueStatsMarkdownRenderer{},
+}
+
+// renderIssueStats dispatches to the issueStatsRenderer registered for
+// outputFormat, falling back to the table renderer for an unrecognized
+// value.
+func renderIssueStats(w io.Writer, stats []LabelStats) error {
+	r, ok := issueStatsRenderers[outputFormat]
+	if !ok {
+		r = issueStatsTableRenderer{}
+	}
+
+	return r.Render(w, stats)
+}
+
+type issueStatsTableRenderer struct{}
+
+func (issueStatsTableRenderer) Render(w io.Writer, stats []LabelStats) error {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{
+		"Label",
+		"# of Issues",
+		"Close Time (mean/median/mad) hours",
+		"First Response (mean/median/mad) hours",
+	})
+
+	for _, s := range stats {
+		t.AppendRow(table.Row{
+			labelOrNone(s.Label),
+			s.Issues,
+			combined(s.CloseTime),
+			combined(s.FirstResponse),
+		})
+	}
+
+	t.AppendSeparator()
+	t.SetStyle(table.StyleColoredBlackOnGreenWhite)
+	t.Render()
+
+	return nil
+}
+
+type issueStatsJSONRenderer struct{}
+
+func (issueStatsJSONRenderer) Render(w io.Writer, stats []LabelStats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(stats)
+}
+
+type issueStatsCSVRenderer struct{}
+
+func (issueStatsCSVRenderer) Render(w io.Writer, stats []LabelStats) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	header := []string{
+		"label", "issues",
+		"close_time_mean", "close_time_median", "close_time_mad",
+		"first_response_mean", "first_response_median", "first_response_mad",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range stats {
+		row := []string{
+			labelOrNone(s.Label), strconv.Itoa(s.Issues),
+			shortFmt(s.CloseTime.Mean), shortFmt(s.CloseTime.Median), shortFmt(s.CloseTime.MedianAbsoluteDeviation),
+			shortFmt(s.FirstResponse.Mean), shortFmt(s.FirstResponse.Median), shortFmt(s.FirstResponse.MedianAbsoluteDeviation),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type issueStatsMarkdownRenderer struct{}
+
+func (issueStatsMarkdownRenderer) Render(w io.Writer, stats []LabelStats) error {
+	fmt.Fprintln(w, "| Label | # of Issues | Close Time (mean/median/mad) hours | First Response (mean/median/mad) hours |")
+	fmt.Fprintln(w, "|---|---|---|---|")
+
+	for _, s := range stats {
+		fmt.Fprintf(w, "| %s | %d | %s | %s |\n",
+			labelOrNone(s.Label), s.Issues, combined(s.CloseTime), combined(s.FirstResponse))
+	}
+
+	return nil
+}
+
+func labelOrNone(label string) string {
+	if label == "" {
+		return "(none)"
+	}
+	return label
+}
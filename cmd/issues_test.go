@@ -0,0 +1,210 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+)
+
+func TestSearchClosedIssues(t *testing.T) {
+	tests := []struct {
+		name   string
+		author string
+		label  string
+		want   string
+	}{
+		{
+			name: "repo and from date only",
+			want: "is:issue is:closed repo:jtsiros/devstats created:>2021-01-01",
+		},
+		{
+			name:   "author filter",
+			author: "jdoe",
+			want:   "is:issue is:closed repo:jtsiros/devstats created:>2021-01-01 author:jdoe",
+		},
+		{
+			name:  "label filter",
+			label: "bug",
+			want:  "is:issue is:closed repo:jtsiros/devstats created:>2021-01-01 label:bug",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuesOpts = SearchIssuesOpts{Repo: "jtsiros/devstats", Author: tt.author, Label: tt.label, FromDate: "2021-01-01"}
+			if got := searchClosedIssues(issuesOpts.Repo); got != tt.want {
+				t.Errorf("searchClosedIssues() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstResponseHours(t *testing.T) {
+	created := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		comments        string
+		wantHasResponse bool
+		wantHours       float64
+	}{
+		{
+			name:            "skips comments by the issue author",
+			comments:        `[{"user": {"login": "author"}, "created_at": "2021-01-01T01:00:00Z"}, {"user": {"login": "other"}, "created_at": "2021-01-01T05:00:00Z"}]`,
+			wantHasResponse: true,
+			wantHours:       5,
+		},
+		{
+			name:            "no comments from anyone else",
+			comments:        `[{"user": {"login": "author"}, "created_at": "2021-01-01T01:00:00Z"}]`,
+			wantHasResponse: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.comments)
+			}))
+			defer srv.Close()
+
+			gc := newTestGithubClient(t, srv)
+			issue := &github.Issue{
+				Number:    github.Int(1),
+				User:      &github.User{Login: github.String("author")},
+				CreatedAt: &github.Timestamp{Time: created},
+			}
+
+			hours, hasResponse, err := firstResponseHours(context.Background(), gc, "jtsiros", "devstats", issue)
+			if err != nil {
+				t.Fatalf("firstResponseHours: %v", err)
+			}
+			if hasResponse != tt.wantHasResponse {
+				t.Errorf("hasResponse = %v, want %v", hasResponse, tt.wantHasResponse)
+			}
+			if tt.wantHasResponse && hours != tt.wantHours {
+				t.Errorf("hours = %v, want %v", hours, tt.wantHours)
+			}
+		})
+	}
+}
+
+func TestLabelStatsFansOutPerLabel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	}))
+	defer srv.Close()
+
+	gc := newTestGithubClient(t, srv)
+
+	created := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	closed := time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	issues := []*github.Issue{
+		{
+			Number:    github.Int(1),
+			User:      &github.User{Login: github.String("author")},
+			CreatedAt: &github.Timestamp{Time: created},
+			ClosedAt:  &github.Timestamp{Time: closed},
+			Labels:    []*github.Label{{Name: github.String("bug")}, {Name: github.String("ui")}},
+		},
+		{
+			Number:    github.Int(2),
+			User:      &github.User{Login: github.String("author")},
+			CreatedAt: &github.Timestamp{Time: created},
+			ClosedAt:  &github.Timestamp{Time: closed},
+		},
+	}
+
+	stats, err := labelStats(context.Background(), gc, "jtsiros/devstats", issues)
+	if err != nil {
+		t.Fatalf("labelStats: %v", err)
+	}
+
+	byLabel := map[string]LabelStats{}
+	for _, s := range stats {
+		byLabel[s.Label] = s
+	}
+
+	if len(byLabel) != 3 {
+		t.Fatalf("labelStats returned %d labels, want 3 (bug, ui, unlabeled)", len(byLabel))
+	}
+	if s := byLabel["bug"]; s.Issues != 1 {
+		t.Errorf("bug issues = %d, want 1", s.Issues)
+	}
+	if s := byLabel["ui"]; s.Issues != 1 {
+		t.Errorf("ui issues = %d, want 1", s.Issues)
+	}
+	if s := byLabel[""]; s.Issues != 1 {
+		t.Errorf("unlabeled issues = %d, want 1 (issue #2 has no labels)", s.Issues)
+	}
+}
+
+func TestRenderIssueStatsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	stats := []LabelStats{{Label: "bug", Issues: 2}}
+
+	if err := (issueStatsJSONRenderer{}).Render(&buf, stats); err != nil {
+		t.Fatalf("issueStatsJSONRenderer.Render: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"Label": "bug"`) {
+		t.Errorf("json output missing label:\n%s", buf.String())
+	}
+}
+
+func TestRenderIssueStatsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	stats := []LabelStats{{Label: "bug", Issues: 2}}
+
+	if err := (issueStatsCSVRenderer{}).Render(&buf, stats); err != nil {
+		t.Fatalf("issueStatsCSVRenderer.Render: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2 (header + 1 data row)", len(lines))
+	}
+	if !strings.HasPrefix(lines[1], "bug,2,") {
+		t.Errorf("data row = %q, want to start with bug,2,", lines[1])
+	}
+}
+
+func TestRenderIssueStatsMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	stats := []LabelStats{{Label: "", Issues: 1}}
+
+	if err := (issueStatsMarkdownRenderer{}).Render(&buf, stats); err != nil {
+		t.Fatalf("issueStatsMarkdownRenderer.Render: %v", err)
+	}
+	if !strings.Contains(buf.String(), "(none)") {
+		t.Errorf("markdown output should render unlabeled issues as (none):\n%s", buf.String())
+	}
+}
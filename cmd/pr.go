@@ -27,19 +27,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
+	"sort"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/google/go-github/v53/github"
-	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/montanaflynn/stats"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
 
 	"golang.org/x/sync/errgroup"
+
+	"github.com/jtsiros/devstats/cache"
+	"github.com/jtsiros/devstats/identity"
 )
 
 func init() {
@@ -59,12 +61,36 @@ func init() {
 		time.Now().AddDate(0, -1, 0).Format("2006-01-02"),
 		"from date to generate PR stats. Defaults to past 30 days",
 	)
+
+	prCmd.Flags().StringVar(&prOpts.CacheDir, "cache-dir", "",
+		"directory to cache fetched PRs in. Defaults to $XDG_CACHE_HOME/devstats")
+
+	prCmd.Flags().BoolVar(&prOpts.Offline, "offline", false,
+		"never call the GitHub API; serve PRs from the cache only, and error if nothing is cached for the repo")
+
+	prCmd.Flags().BoolVar(&prOpts.Refresh, "refresh", false,
+		"bypass the cache and refetch every PR from the GitHub API")
+
+	prCmd.Flags().StringVar(&prOpts.Identities, "identities", "",
+		"path to a YAML/JSON file mapping aliases to a canonical contributor")
+
+	prCmd.Flags().BoolVar(&prOpts.IdentitiesDiscover, "identities-discover", false,
+		"scan commit authors on fetched PRs and print suggested identity mappings")
+
+	prCmd.Flags().Float64Var(&prOpts.Trim, "trim", 0.0,
+		"fraction to trim from both ends of merge-time data before computing Mean (e.g. 0.1 trims the top+bottom decile)")
 }
 
 type SearchPROpts struct {
-	FromDate string
-	Authors  []string
-	Repo     string
+	FromDate           string
+	Authors            []string
+	Repo               string
+	CacheDir           string
+	Offline            bool
+	Refresh            bool
+	Identities         string
+	IdentitiesDiscover bool
+	Trim               float64
 }
 
 const (
@@ -77,6 +103,7 @@ const (
 var (
 	prOpts     = SearchPROpts{}
 	errRepoFmt = errors.New("repo format error: must provide owner and repo. ex: jtsiros/devstats")
+	errSkipPR  = errors.New("pr: could not fetch from the API, skipping")
 )
 
 var prCmd = &cobra.Command{
@@ -110,24 +137,66 @@ type Statistics struct {
 	Mean                    float64
 	Median                  float64
 	MedianAbsoluteDeviation float64
+	P75                     float64
+	P90                     float64
+	P95                     float64
+	IQR                     float64
 }
 
+// calcStats computes Mean/Median/MAD and the percentile/IQR fields with no
+// trimming. Use calcStatsTrimmed when outliers should be excluded from the
+// Mean.
 func calcStats(s []float64) Statistics {
+	return calcStatsTrimmed(s, 0.0)
+}
+
+// calcStatsTrimmed is calcStats with trim applied to the Mean only: Median,
+// MedianAbsoluteDeviation, and the percentile/IQR fields are always computed
+// on the untrimmed data, since trimming only targets outlier-sensitive Mean.
+func calcStatsTrimmed(s []float64, trim float64) Statistics {
 	if len(s) == 0 {
 		return Statistics{}
 	}
 
-	mR, _ := stats.Mean(s)
+	mR, _ := stats.Mean(trimSlice(s, trim))
 	medR, _ := stats.Median(s)
 	madR, _ := stats.MedianAbsoluteDeviation(s)
+	q1, _ := stats.Percentile(s, 25)
+	q3, _ := stats.Percentile(s, 75)
+	p90, _ := stats.Percentile(s, 90)
+	p95, _ := stats.Percentile(s, 95)
 
 	return Statistics{
 		Mean:                    mR,
 		Median:                  medR,
 		MedianAbsoluteDeviation: madR,
+		P75:                     q3,
+		P90:                     p90,
+		P95:                     p95,
+		IQR:                     q3 - q1,
 	}
 }
 
+// trimSlice sorts a copy of s and drops the top and bottom trim fraction
+// (e.g. trim=0.1 drops the bottom and top decile) before it's handed to
+// stats.Mean.
+func trimSlice(s []float64, trim float64) []float64 {
+	if trim <= 0 {
+		return s
+	}
+
+	sorted := make([]float64, len(s))
+	copy(sorted, s)
+	sort.Float64s(sorted)
+
+	cut := int(float64(len(sorted)) * trim)
+	if cut*2 >= len(sorted) {
+		return sorted
+	}
+
+	return sorted[cut : len(sorted)-cut]
+}
+
 func run() error {
 	ctx := context.Background()
 	t := oauth2.StaticTokenSource(
@@ -136,33 +205,166 @@ func run() error {
 	oc := oauth2.NewClient(ctx, t)
 	gc := github.NewClient(oc)
 
-	var cstats []ContributorStats
-	fmt.Printf("Groking PR stats for %s from [%s]...\n", prOpts.Authors, prOpts.FromDate)
+	var src Source = &githubSource{gc: gc, repo: prOpts.Repo}
 
-	for _, a := range prOpts.Authors {
-		prs, err := pullRequests(ctx, gc, prOpts.Repo, a)
+	since, err := time.Parse("2006-01-02", prOpts.FromDate)
+	if err != nil {
+		return err
+	}
+
+	resolver := identity.NewResolver(nil)
+	if prOpts.Identities != "" {
+		r, err := identity.Load(prOpts.Identities)
 		if err != nil {
 			return err
 		}
+		resolver = r
+	}
 
-		s := calculateStats(prs)
-		s.Author = a
+	var cstats []ContributorStats
+	fmt.Printf("Groking PR stats for %s from [%s]...\n", prOpts.Authors, prOpts.FromDate)
+
+	for _, a := range dedupeAuthors(prOpts.Authors, resolver) {
+		aliases := resolver.Aliases(a)
+
+		var contributions []Contribution
+		var allPRs []*github.PullRequest
+
+		for _, alias := range aliases {
+			// --identities-discover needs the raw PRs to scan commit
+			// authors, so fetch those directly and derive contributions
+			// from them instead of also calling src.FetchMerged, which
+			// would fetch (or re-fetch under --refresh) the same PRs again.
+			if prOpts.IdentitiesDiscover {
+				prs, err := pullRequests(ctx, gc, prOpts.Repo, alias)
+				if err != nil {
+					return err
+				}
+				allPRs = append(allPRs, prs...)
+				contributions = append(contributions, contributionsFromPRs(prs)...)
+				continue
+			}
+
+			cs, err := src.FetchMerged(ctx, alias, since)
+			if err != nil {
+				return err
+			}
+			contributions = append(contributions, cs...)
+		}
+
+		if prOpts.IdentitiesDiscover {
+			if err := suggestIdentities(ctx, gc, prOpts.Repo, allPRs, resolver); err != nil {
+				return err
+			}
+		}
+
+		s := calculateStatsFromContributions(contributions, prOpts.Trim)
+		s.Author = resolver.Canonical(a)
 		cstats = append(cstats, s)
 	}
 
 	fmt.Println(colorGreen, "finished")
-	render(cstats)
+	render(Meta{Repo: prOpts.Repo, FromDate: prOpts.FromDate}, cstats)
+
+	return nil
+}
+
+// dedupeAuthors collapses --author values that the resolver maps to the
+// same canonical contributor, keeping the first occurrence of each. Without
+// this, supplying two aliases of one person (the exact case --identities
+// targets) would fetch and render that person's PRs twice.
+func dedupeAuthors(authors []string, resolver *identity.Resolver) []string {
+	seen := map[string]bool{}
+	deduped := make([]string, 0, len(authors))
+
+	for _, a := range authors {
+		canonical := resolver.Canonical(a)
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		deduped = append(deduped, a)
+	}
+
+	return deduped
+}
+
+// suggestIdentities scans each PR's commits for author logins/emails that
+// the resolver doesn't already know about and prints them as candidate
+// additions to the --identities file.
+func suggestIdentities(ctx context.Context, gc *github.Client, repo string, prs []*github.PullRequest, resolver *identity.Resolver) error {
+	ownerAndRepo := strings.Split(repo, "/")
+	if len(ownerAndRepo) != ownerRepoTokenLen {
+		return errRepoFmt
+	}
+
+	seen := map[string]bool{}
+
+	for _, pr := range prs {
+		commits, _, err := gc.PullRequests.ListCommits(ctx,
+			ownerAndRepo[0], ownerAndRepo[1], pr.GetNumber(), nil)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range commits {
+			login := c.GetAuthor().GetLogin()
+			email := c.GetCommit().GetAuthor().GetEmail()
+
+			if login == "" || resolver.KnowsLogin(login) || resolver.KnowsEmail(email) {
+				continue
+			}
+
+			key := login + "|" + email
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			fmt.Printf("suggested identity: login=%s email=%s (seen on PR #%d)\n",
+				login, email, pr.GetNumber())
+		}
+	}
 
 	return nil
 }
 
-// pullRequests fetches all PRs created by the author from a given date (default 30 days).
+// githubSource implements Source by wrapping the search-then-fetch flow in
+// pullRequests. It is the GitHub counterpart of gerrit.go's gerritSource.
+type githubSource struct {
+	gc   *github.Client
+	repo string
+}
+
+func (s *githubSource) FetchMerged(ctx context.Context, author string, since time.Time) ([]Contribution, error) {
+	prs, err := pullRequests(ctx, s.gc, s.repo, author)
+	if err != nil {
+		return nil, err
+	}
+
+	return contributionsFromPRs(prs), nil
+}
+
+// pullRequests fetches all PRs created by the author from a given date
+// (default 30 days). --offline serves entirely from the cache via
+// offlineCandidates; otherwise it enumerates candidate PR numbers via a
+// cheap Search.Issues call, then only falls through to the more expensive
+// PullRequests.Get when the cache is missing or stale, per --refresh.
 func pullRequests(ctx context.Context, gc *github.Client, repo string, author string) ([]*github.PullRequest, error) {
 	ownerAndRepo := strings.Split(repo, "/")
 	if len(ownerAndRepo) != ownerRepoTokenLen {
 		return nil, errRepoFmt
 	}
 
+	pc, err := cache.New(prOpts.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if prOpts.Offline {
+		return offlineCandidates(pc, ownerAndRepo[0], ownerAndRepo[1], author)
+	}
+
 	opt := &github.SearchOptions{
 		ListOptions: github.ListOptions{PerPage: 100},
 	}
@@ -210,28 +412,13 @@ func pullRequests(ctx context.Context, gc *github.Client, repo string, author st
 					continue
 				}
 
-				pr, resp, err := gc.PullRequests.Get(ctx,
-					ownerAndRepo[0],
-					ownerAndRepo[1],
-					i.GetNumber(),
-				)
+				pr, err := fetchPR(ctx, gc, pc, ownerAndRepo[0], ownerAndRepo[1], i.GetNumber(), i.GetUpdatedAt().Time)
 				if err != nil {
-					// skip processing this PR since we couldn't fetch it.
-					continue
-				}
-
-				if resp.StatusCode != 200 {
-					body, _ := io.ReadAll(resp.Body)
-					err := resp.Body.Close()
-					if err != nil {
-						return err
+					if errors.Is(err, errSkipPR) {
+						// the API call failed; skip this PR rather than failing the whole run.
+						continue
 					}
-
-					return fmt.Errorf("PR GET (%d): [%d] - %s",
-						resp.StatusCode,
-						i.GetNumber(),
-						body,
-					)
+					return err
 				}
 
 				select {
@@ -253,57 +440,80 @@ func pullRequests(ctx context.Context, gc *github.Client, repo string, author st
 	return allPRs, g.Wait()
 }
 
-func calculateStats(prs []*github.PullRequest) ContributorStats {
-	mergeDeltas := make([]float64, len(prs))
-	commits := make([]float64, len(prs))
-	comments := make([]float64, len(prs))
-	changeSize := make([]float64, len(prs))
-
-	for i, pr := range prs {
-		delta := pr.GetMergedAt().Sub(pr.GetCreatedAt().Time).Hours()
-		mergeDeltas[i] = delta
-		changeSize[i] = float64(pr.GetAdditions() + pr.GetDeletions())
-		commits[i] = float64(pr.GetCommits())
-		comments[i] = float64(pr.GetComments())
+// offlineCandidates serves pullRequests entirely from the cache, for
+// --offline: it lists every PR cached for owner/repo and filters it down to
+// the merged PRs matching author/--from, the same criteria searchByAuthor
+// would otherwise ask GitHub's search API for.
+func offlineCandidates(pc *cache.Cache, owner, repo, author string) ([]*github.PullRequest, error) {
+	numbers, err := pc.List(owner, repo)
+	if err != nil {
+		return nil, err
 	}
 
-	return ContributorStats{
-		MergeTime:  calcStats(mergeDeltas),
-		Commits:    calcStats(commits),
-		ChangeSize: calcStats(changeSize),
-		Comments:   calcStats(comments),
-		PRs:        len(prs),
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("--offline: no cached PRs for %s/%s", owner, repo)
 	}
+
+	var from time.Time
+	if len(prOpts.FromDate) != 0 {
+		from, err = time.Parse("2006-01-02", prOpts.FromDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var prs []*github.PullRequest
+	for _, n := range numbers {
+		pr, err := pc.Get(owner, repo, n)
+		if err != nil {
+			return nil, fmt.Errorf("--offline: %s/%s#%d: %w", owner, repo, n, err)
+		}
+
+		if pr.GetMergedAt().IsZero() || pr.GetUser().GetLogin() != author {
+			continue
+		}
+
+		// searchByAuthor's created:>FromDate excludes the from date itself.
+		if !from.IsZero() && pr.GetCreatedAt().Time.Before(from.AddDate(0, 0, 1)) {
+			continue
+		}
+
+		prs = append(prs, pr)
+	}
+
+	return prs, nil
 }
 
-func render(stats []ContributorStats) {
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{
-		"Author",
-		"Merge Time (mean/median/mad) hours",
-		"Comments (mean/median/mad)",
-		"Commits (mean/median/mad)",
-		"Change Size +/- (mean/median/mad)",
-		"# of PRs",
-	})
+// fetchPR returns the PR for owner/repo#number, preferring the cache when
+// it's fresh relative to updatedAt. --refresh always hits the API; an
+// ordinary API failure returns errSkipPR so the caller can skip just that
+// PR. pullRequests only calls this when online, so there's no --offline
+// branch here: see offlineCandidates.
+func fetchPR(ctx context.Context, gc *github.Client, pc *cache.Cache, owner, repo string, number int, updatedAt time.Time) (*github.PullRequest, error) {
+	if !prOpts.Refresh && !pc.Stale(owner, repo, number, updatedAt) {
+		return pc.Get(owner, repo, number)
+	}
 
-	var prs int
-	for _, s := range stats {
-		prs += s.PRs
-		t.AppendRow(table.Row{
-			s.Author,
-			combined(s.MergeTime),
-			combined(s.Comments),
-			combined(s.Commits),
-			combined(s.ChangeSize),
-			s.PRs,
-		})
+	pr, resp, err := gc.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		// skip processing this PR since we couldn't fetch it.
+		return nil, errSkipPR
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		if err := resp.Body.Close(); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("PR GET (%d): [%d] - %s", resp.StatusCode, number, body)
 	}
 
-	t.AppendSeparator()
-	t.SetStyle(table.StyleColoredBlackOnGreenWhite)
-	t.Render()
+	if err := pc.Put(owner, repo, pr); err != nil {
+		return nil, err
+	}
+
+	return pr, nil
 }
 
 func combined(s Statistics) string {
@@ -313,6 +523,15 @@ func combined(s Statistics) string {
 		shortFmt(s.MedianAbsoluteDeviation))
 }
 
+// percentiles renders the p50/p90/p95 compact column shown alongside
+// combined's mean/median/mad column.
+func percentiles(s Statistics) string {
+	return fmt.Sprintf("%s/%s/%s",
+		shortFmt(s.Median),
+		shortFmt(s.P90),
+		shortFmt(s.P95))
+}
+
 func shortFmt(f float64) string {
 	return fmt.Sprintf("%.2f", f)
 }
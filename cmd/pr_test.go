@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+
+	"github.com/jtsiros/devstats/cache"
+	"github.com/jtsiros/devstats/identity"
+)
+
+func TestTrimSlice(t *testing.T) {
+	s := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	if got := trimSlice(s, 0); len(got) != len(s) {
+		t.Errorf("trimSlice(0) = %v, want unchanged", got)
+	}
+
+	got := trimSlice(s, 0.2)
+	want := []float64{3, 4, 5, 6, 7, 8}
+	if len(got) != len(want) {
+		t.Fatalf("trimSlice(0.2) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("trimSlice(0.2) = %v, want %v", got, want)
+			break
+		}
+	}
+
+	// trim large enough to cut everything should fall back to the full slice.
+	if got := trimSlice(s, 0.9); len(got) != len(s) {
+		t.Errorf("trimSlice(0.9) = %v, want unchanged fallback", got)
+	}
+}
+
+func TestCalcStatsTrimmedEmpty(t *testing.T) {
+	if got := calcStatsTrimmed(nil, 0.1); got != (Statistics{}) {
+		t.Errorf("calcStatsTrimmed(nil) = %+v, want zero value", got)
+	}
+}
+
+func TestCalcStatsTrimmedOnlyAffectsMean(t *testing.T) {
+	s := []float64{1, 2, 3, 4, 100}
+
+	untrimmed := calcStatsTrimmed(s, 0)
+	trimmed := calcStatsTrimmed(s, 0.2)
+
+	if trimmed.Mean >= untrimmed.Mean {
+		t.Errorf("trimmed mean %v should be less than untrimmed mean %v", trimmed.Mean, untrimmed.Mean)
+	}
+
+	if trimmed.Median != untrimmed.Median {
+		t.Errorf("trim should not change Median: trimmed=%v untrimmed=%v", trimmed.Median, untrimmed.Median)
+	}
+
+	if trimmed.P95 != untrimmed.P95 {
+		t.Errorf("trim should not change P95: trimmed=%v untrimmed=%v", trimmed.P95, untrimmed.P95)
+	}
+}
+
+func TestDedupeAuthors(t *testing.T) {
+	resolver := identity.NewResolver([]identity.Person{
+		{ID: "jdoe", GithubLogins: []string{"jdoe", "jane"}},
+	})
+
+	got := dedupeAuthors([]string{"jdoe", "jane", "bob"}, resolver)
+	want := []string{"jdoe", "bob"}
+
+	if len(got) != len(want) {
+		t.Fatalf("dedupeAuthors() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("dedupeAuthors() = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestOfflineCandidatesErrorsOnEmptyCache(t *testing.T) {
+	pc, err := cache.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.New: %v", err)
+	}
+
+	if _, err := offlineCandidates(pc, "jtsiros", "devstats", "jdoe"); err == nil {
+		t.Error("offlineCandidates() with nothing cached = nil error, want an error per --offline's contract")
+	}
+}
+
+func TestOfflineCandidatesFiltersByAuthorMergedAndFromDate(t *testing.T) {
+	pc, err := cache.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.New: %v", err)
+	}
+
+	put := func(number int, login string, merged bool, createdAt time.Time) {
+		pr := &github.PullRequest{
+			Number:    github.Int(number),
+			User:      &github.User{Login: github.String(login)},
+			CreatedAt: &github.Timestamp{Time: createdAt},
+		}
+		if merged {
+			pr.MergedAt = &github.Timestamp{Time: createdAt.Add(time.Hour)}
+		}
+		if err := pc.Put("jtsiros", "devstats", pr); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	put(1, "jdoe", true, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))  // matches
+	put(2, "jdoe", false, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)) // not merged
+	put(3, "other", true, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)) // wrong author
+	put(4, "jdoe", true, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))  // before --from
+
+	oldOpts := prOpts
+	prOpts = SearchPROpts{FromDate: "2021-03-01"}
+	defer func() { prOpts = oldOpts }()
+
+	prs, err := offlineCandidates(pc, "jtsiros", "devstats", "jdoe")
+	if err != nil {
+		t.Fatalf("offlineCandidates: %v", err)
+	}
+	if len(prs) != 1 || prs[0].GetNumber() != 1 {
+		t.Errorf("offlineCandidates() = %v, want only PR #1", prs)
+	}
+}
+
+func TestFetchPRPrefersFreshCache(t *testing.T) {
+	pc, err := cache.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.New: %v", err)
+	}
+
+	updatedAt := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	if err := pc.Put("jtsiros", "devstats", &github.PullRequest{
+		Number:    github.Int(1),
+		UpdatedAt: &github.Timestamp{Time: updatedAt},
+	}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected API call for a fresh cache entry: %s", r.URL)
+	}))
+	defer srv.Close()
+	gc := newTestGithubClient(t, srv)
+
+	pr, err := fetchPR(context.Background(), gc, pc, "jtsiros", "devstats", 1, updatedAt)
+	if err != nil {
+		t.Fatalf("fetchPR: %v", err)
+	}
+	if pr.GetNumber() != 1 {
+		t.Errorf("fetchPR() = %+v, want PR #1 served from cache", pr)
+	}
+}
+
+func TestFetchPRRefetchesStaleAndCaches(t *testing.T) {
+	pc, err := cache.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.New: %v", err)
+	}
+
+	updatedAt := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"number": 1, "updated_at": %q}`, updatedAt.Format(time.RFC3339))
+	}))
+	defer srv.Close()
+	gc := newTestGithubClient(t, srv)
+
+	pr, err := fetchPR(context.Background(), gc, pc, "jtsiros", "devstats", 1, updatedAt)
+	if err != nil {
+		t.Fatalf("fetchPR: %v", err)
+	}
+	if pr.GetNumber() != 1 {
+		t.Errorf("fetchPR() = %+v, want PR #1", pr)
+	}
+
+	cached, err := pc.Get("jtsiros", "devstats", 1)
+	if err != nil {
+		t.Fatalf("expected fetchPR to have cached the result: %v", err)
+	}
+	if cached.GetNumber() != 1 {
+		t.Errorf("cached PR = %+v, want #1", cached)
+	}
+}
+
+func TestSuggestIdentitiesSkipsKnownAliases(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"author": {"login": "jdoe"}, "commit": {"author": {"name": "Jane Doe", "email": "jdoe@example.com"}}},
+			{"author": {"login": "newbie"}, "commit": {"author": {"name": "New Person", "email": "newbie@example.com"}}}
+		]`)
+	}))
+	defer srv.Close()
+	gc := newTestGithubClient(t, srv)
+
+	resolver := identity.NewResolver([]identity.Person{
+		{ID: "jdoe", GithubLogins: []string{"jdoe"}, Emails: []string{"jdoe@example.com"}},
+	})
+
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdout = w
+
+	err = suggestIdentities(context.Background(), gc, "jtsiros/devstats",
+		[]*github.PullRequest{{Number: github.Int(42)}}, resolver)
+
+	w.Close()
+	os.Stdout = old
+
+	if err != nil {
+		t.Fatalf("suggestIdentities: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		t.Fatalf("io.Copy: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "jdoe") {
+		t.Errorf("suggestIdentities should not suggest a login the resolver already knows:\n%s", out)
+	}
+	if !strings.Contains(out, "newbie") {
+		t.Errorf("suggestIdentities should suggest the unknown login:\n%s", out)
+	}
+}
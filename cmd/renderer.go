@@ -0,0 +1,190 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "table",
+		"output format for contributor stats: table, json, csv, or markdown")
+}
+
+var outputFormat string
+
+// Renderer formats a set of ContributorStats for display or machine
+// consumption. table, json, csv, and markdown all satisfy this so render
+// can dispatch on the --format flag without pr.go/gerrit.go knowing about
+// output formats at all. meta describes the run that produced stats (which
+// repo/host, which from-date) so callers don't have to reach into another
+// command's package-level opts to report it.
+type Renderer interface {
+	Render(w io.Writer, meta Meta, stats []ContributorStats) error
+}
+
+// renderers is the format-name-to-Renderer registry consulted by render.
+var renderers = map[string]Renderer{
+	"table":    tableRenderer{},
+	"json":     jsonRenderer{},
+	"csv":      csvRenderer{},
+	"markdown": markdownRenderer{},
+}
+
+// Meta describes the run that produced a set of ContributorStats, included
+// in the json renderer's output so downstream consumers know what the
+// numbers cover.
+type Meta struct {
+	Repo        string
+	FromDate    string
+	GeneratedAt time.Time
+}
+
+// render dispatches to the Renderer registered for outputFormat, falling
+// back to the table renderer for an unrecognized value.
+func render(meta Meta, stats []ContributorStats) {
+	r, ok := renderers[outputFormat]
+	if !ok {
+		r = tableRenderer{}
+	}
+
+	if err := r.Render(os.Stdout, meta, stats); err != nil {
+		fmt.Fprintln(os.Stderr, "render:", err)
+	}
+}
+
+type tableRenderer struct{}
+
+func (tableRenderer) Render(w io.Writer, meta Meta, stats []ContributorStats) error {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{
+		"Author",
+		"Merge Time (mean/median/mad) hours",
+		"Merge Time (p50/p90/p95) hours",
+		"Comments (mean/median/mad)",
+		"Commits (mean/median/mad)",
+		"Change Size +/- (mean/median/mad)",
+		"# of PRs",
+	})
+
+	for _, s := range stats {
+		t.AppendRow(table.Row{
+			s.Author,
+			combined(s.MergeTime),
+			percentiles(s.MergeTime),
+			combined(s.Comments),
+			combined(s.Commits),
+			combined(s.ChangeSize),
+			s.PRs,
+		})
+	}
+
+	t.AppendSeparator()
+	t.SetStyle(table.StyleColoredBlackOnGreenWhite)
+	t.Render()
+
+	return nil
+}
+
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(w io.Writer, meta Meta, stats []ContributorStats) error {
+	meta.GeneratedAt = time.Now()
+
+	payload := struct {
+		Meta  Meta
+		Stats []ContributorStats
+	}{
+		Meta:  meta,
+		Stats: stats,
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(payload)
+}
+
+type csvRenderer struct{}
+
+func (csvRenderer) Render(w io.Writer, meta Meta, stats []ContributorStats) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	header := []string{
+		"author", "prs",
+		"merge_time_mean", "merge_time_median", "merge_time_mad",
+		"merge_time_p75", "merge_time_p90", "merge_time_p95", "merge_time_iqr",
+		"comments_mean", "comments_median", "comments_mad",
+		"commits_mean", "commits_median", "commits_mad",
+		"change_size_mean", "change_size_median", "change_size_mad",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range stats {
+		row := []string{
+			s.Author, strconv.Itoa(s.PRs),
+			shortFmt(s.MergeTime.Mean), shortFmt(s.MergeTime.Median), shortFmt(s.MergeTime.MedianAbsoluteDeviation),
+			shortFmt(s.MergeTime.P75), shortFmt(s.MergeTime.P90), shortFmt(s.MergeTime.P95), shortFmt(s.MergeTime.IQR),
+			shortFmt(s.Comments.Mean), shortFmt(s.Comments.Median), shortFmt(s.Comments.MedianAbsoluteDeviation),
+			shortFmt(s.Commits.Mean), shortFmt(s.Commits.Median), shortFmt(s.Commits.MedianAbsoluteDeviation),
+			shortFmt(s.ChangeSize.Mean), shortFmt(s.ChangeSize.Median), shortFmt(s.ChangeSize.MedianAbsoluteDeviation),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type markdownRenderer struct{}
+
+func (markdownRenderer) Render(w io.Writer, meta Meta, stats []ContributorStats) error {
+	fmt.Fprintln(w, "| Author | Merge Time (mean/median/mad) | Merge Time (p50/p90/p95) | Comments (mean/median/mad) | Commits (mean/median/mad) | Change Size (mean/median/mad) | # of PRs |")
+	fmt.Fprintln(w, "|---|---|---|---|---|---|---|")
+
+	for _, s := range stats {
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %d |\n",
+			s.Author,
+			combined(s.MergeTime),
+			percentiles(s.MergeTime),
+			combined(s.Comments),
+			combined(s.Commits),
+			combined(s.ChangeSize),
+			s.PRs,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// sampleStats is the []ContributorStats fixture shared by every renderer
+// test below.
+func sampleStats() []ContributorStats {
+	return []ContributorStats{
+		{
+			Author:     "Jane Doe",
+			PRs:        5,
+			MergeTime:  Statistics{Mean: 12.5, Median: 10, MedianAbsoluteDeviation: 2, P75: 15, P90: 20, P95: 22, IQR: 5},
+			Comments:   Statistics{Mean: 3, Median: 2, MedianAbsoluteDeviation: 1},
+			Commits:    Statistics{Mean: 4, Median: 3, MedianAbsoluteDeviation: 1},
+			ChangeSize: Statistics{Mean: 100, Median: 90, MedianAbsoluteDeviation: 10},
+		},
+	}
+}
+
+func TestTableRendererRender(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (tableRenderer{}).Render(&buf, Meta{Repo: "jtsiros/devstats"}, sampleStats()); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"Jane Doe", "# OF PRS", "5"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestJSONRendererRender(t *testing.T) {
+	var buf bytes.Buffer
+	meta := Meta{Repo: "jtsiros/devstats", FromDate: "2021-01-01"}
+	if err := (jsonRenderer{}).Render(&buf, meta, sampleStats()); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	var payload struct {
+		Meta  Meta
+		Stats []ContributorStats
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if payload.Meta.Repo != meta.Repo || payload.Meta.FromDate != meta.FromDate {
+		t.Errorf("Meta = %+v, want Repo/FromDate from %+v", payload.Meta, meta)
+	}
+	if payload.Meta.GeneratedAt.IsZero() {
+		t.Error("Meta.GeneratedAt was not stamped")
+	}
+	if len(payload.Stats) != 1 || payload.Stats[0].Author != "Jane Doe" {
+		t.Errorf("Stats = %+v, want one entry for Jane Doe", payload.Stats)
+	}
+}
+
+func TestCSVRendererRender(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (csvRenderer{}).Render(&buf, Meta{}, sampleStats()); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 (header + 1 data row)", len(rows))
+	}
+
+	wantHeader := []string{
+		"author", "prs",
+		"merge_time_mean", "merge_time_median", "merge_time_mad",
+		"merge_time_p75", "merge_time_p90", "merge_time_p95", "merge_time_iqr",
+		"comments_mean", "comments_median", "comments_mad",
+		"commits_mean", "commits_median", "commits_mad",
+		"change_size_mean", "change_size_median", "change_size_mad",
+	}
+	if strings.Join(rows[0], ",") != strings.Join(wantHeader, ",") {
+		t.Errorf("header = %v, want %v", rows[0], wantHeader)
+	}
+
+	if rows[1][0] != "Jane Doe" {
+		t.Errorf("row[0] author = %q, want Jane Doe", rows[1][0])
+	}
+	if rows[1][1] != "5" {
+		t.Errorf("row[0] prs = %q, want 5 (integer, not 5.00)", rows[1][1])
+	}
+}
+
+func TestMarkdownRendererRender(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (markdownRenderer{}).Render(&buf, Meta{}, sampleStats()); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3 (header + separator + 1 data row)", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "| Author |") {
+		t.Errorf("header row = %q", lines[0])
+	}
+	if !strings.Contains(lines[2], "Jane Doe") || !strings.Contains(lines[2], "| 5 |") {
+		t.Errorf("data row = %q, want Jane Doe and 5 PRs", lines[2])
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+)
+
+// Contribution is a source-neutral view of a single unit of review work
+// (a GitHub pull request, a Gerrit change, etc.) that carries everything
+// calculateStatsFromContributions needs to derive ContributorStats.
+type Contribution struct {
+	CreatedAt time.Time
+	MergedAt  time.Time
+	Additions int
+	Deletions int
+	Comments  int
+
+	// Patchsets is the Gerrit analogue of a GitHub PR's commit count.
+	Patchsets int
+}
+
+// Source fetches an author's merged contributions from a code review
+// system. pr.go and gerrit.go each provide an implementation.
+type Source interface {
+	FetchMerged(ctx context.Context, author string, since time.Time) ([]Contribution, error)
+}
+
+// calculateStatsFromContributions reduces Contributions into the
+// Mean/Median/MAD statistics reported for an author. trim only applies to
+// MergeTime's Mean.
+func calculateStatsFromContributions(contributions []Contribution, trim float64) ContributorStats {
+	mergeDeltas := make([]float64, len(contributions))
+	commits := make([]float64, len(contributions))
+	comments := make([]float64, len(contributions))
+	changeSize := make([]float64, len(contributions))
+
+	for i, c := range contributions {
+		mergeDeltas[i] = c.MergedAt.Sub(c.CreatedAt).Hours()
+		changeSize[i] = float64(c.Additions + c.Deletions)
+		commits[i] = float64(c.Patchsets)
+		comments[i] = float64(c.Comments)
+	}
+
+	return ContributorStats{
+		MergeTime:  calcStatsTrimmed(mergeDeltas, trim),
+		Commits:    calcStats(commits),
+		ChangeSize: calcStats(changeSize),
+		Comments:   calcStats(comments),
+		PRs:        len(contributions),
+	}
+}
+
+// contributionsFromPRs adapts GitHub pull requests, as fetched by
+// pullRequests, to the neutral Contribution shape.
+func contributionsFromPRs(prs []*github.PullRequest) []Contribution {
+	contributions := make([]Contribution, len(prs))
+	for i, pr := range prs {
+		contributions[i] = Contribution{
+			CreatedAt: pr.GetCreatedAt().Time,
+			MergedAt:  pr.GetMergedAt().Time,
+			Additions: pr.GetAdditions(),
+			Deletions: pr.GetDeletions(),
+			Comments:  pr.GetComments(),
+			Patchsets: pr.GetCommits(),
+		}
+	}
+
+	return contributions
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2021 Jon Tsiros jon.tsiros@brightblock.ai
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package identity resolves GitHub logins to a single canonical
+// contributor, so that someone who commits under more than one account
+// isn't undercounted as several separate people. Emails are recorded per
+// person and can be checked with KnowsEmail, but are not resolved to a
+// contributor; free-form names are not resolved at all.
+package identity
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Person is a canonical contributor and every alias known to resolve to them.
+type Person struct {
+	ID           string   `json:"id" yaml:"id"`
+	DisplayName  string   `json:"displayName" yaml:"displayName"`
+	GithubLogins []string `json:"githubLogins" yaml:"githubLogins"`
+	Emails       []string `json:"emails" yaml:"emails"`
+}
+
+// Resolver maps aliases to their canonical Person.
+type Resolver struct {
+	people  []Person
+	byLogin map[string]*Person
+	byEmail map[string]*Person
+}
+
+// NewResolver builds a Resolver from an explicit list of people. A nil or
+// empty list is valid and yields a Resolver where every alias resolves to
+// itself.
+func NewResolver(people []Person) *Resolver {
+	r := &Resolver{
+		people:  people,
+		byLogin: map[string]*Person{},
+		byEmail: map[string]*Person{},
+	}
+
+	for i := range r.people {
+		p := &r.people[i]
+		for _, login := range p.GithubLogins {
+			r.byLogin[strings.ToLower(login)] = p
+		}
+		for _, email := range p.Emails {
+			r.byEmail[strings.ToLower(email)] = p
+		}
+	}
+
+	return r
+}
+
+// Load reads a Resolver's Person list from a YAML or JSON file, chosen by
+// the file's extension (.json vs anything else, defaulting to YAML).
+func Load(path string) (*Resolver, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var people []Person
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(b, &people)
+	} else {
+		err = yaml.Unmarshal(b, &people)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResolver(people), nil
+}
+
+// Canonical returns the display name for a known login, or the login
+// itself when it isn't mapped to anyone.
+func (r *Resolver) Canonical(login string) string {
+	if p, ok := r.byLogin[strings.ToLower(login)]; ok {
+		return p.DisplayName
+	}
+
+	return login
+}
+
+// Aliases returns every GitHub login known to belong to the same
+// contributor as login, or just []string{login} when it isn't mapped.
+func (r *Resolver) Aliases(login string) []string {
+	p, ok := r.byLogin[strings.ToLower(login)]
+	if !ok || len(p.GithubLogins) == 0 {
+		return []string{login}
+	}
+
+	return p.GithubLogins
+}
+
+// KnowsEmail reports whether email is already mapped to a Person.
+func (r *Resolver) KnowsEmail(email string) bool {
+	_, ok := r.byEmail[strings.ToLower(email)]
+	return ok
+}
+
+// KnowsLogin reports whether login is already mapped to a Person.
+func (r *Resolver) KnowsLogin(login string) bool {
+	_, ok := r.byLogin[strings.ToLower(login)]
+	return ok
+}
@@ -0,0 +1,100 @@
+package identity
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolverCanonicalAndAliases(t *testing.T) {
+	r := NewResolver([]Person{
+		{
+			ID:           "jdoe",
+			DisplayName:  "Jane Doe",
+			GithubLogins: []string{"jdoe", "jane-doe"},
+			Emails:       []string{"jane@example.com"},
+		},
+	})
+
+	if got := r.Canonical("jane-doe"); got != "Jane Doe" {
+		t.Errorf("Canonical(jane-doe) = %q, want Jane Doe", got)
+	}
+
+	if got := r.Canonical("unknown"); got != "unknown" {
+		t.Errorf("Canonical(unknown) = %q, want unknown", got)
+	}
+
+	aliases := r.Aliases("jdoe")
+	if len(aliases) != 2 || aliases[0] != "jdoe" || aliases[1] != "jane-doe" {
+		t.Errorf("Aliases(jdoe) = %v, want [jdoe jane-doe]", aliases)
+	}
+
+	if got := r.Aliases("unknown"); len(got) != 1 || got[0] != "unknown" {
+		t.Errorf("Aliases(unknown) = %v, want [unknown]", got)
+	}
+
+	if !r.KnowsLogin("jdoe") || r.KnowsLogin("unknown") {
+		t.Errorf("KnowsLogin mismatch for jdoe/unknown")
+	}
+
+	if !r.KnowsLogin("JDoe") {
+		t.Errorf("KnowsLogin(JDoe) should match jdoe case-insensitively")
+	}
+
+	if got := r.Canonical("JDoe"); got != "Jane Doe" {
+		t.Errorf("Canonical(JDoe) = %q, want Jane Doe", got)
+	}
+
+	if !r.KnowsEmail("Jane@Example.com") || r.KnowsEmail("nobody@example.com") {
+		t.Errorf("KnowsEmail mismatch for jane@example.com/nobody@example.com")
+	}
+}
+
+func TestNewResolverEmpty(t *testing.T) {
+	r := NewResolver(nil)
+
+	if got := r.Canonical("someone"); got != "someone" {
+		t.Errorf("Canonical(someone) = %q, want someone", got)
+	}
+
+	if r.KnowsLogin("someone") || r.KnowsEmail("someone@example.com") {
+		t.Errorf("empty resolver should know nothing")
+	}
+}
+
+func TestLoadYAMLAndJSON(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "identities.yaml")
+	yamlContent := `
+- id: jdoe
+  displayName: Jane Doe
+  githubLogins: [jdoe]
+  emails: [jane@example.com]
+`
+	if err := os.WriteFile(yamlPath, []byte(yamlContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Load(yamlPath)
+	if err != nil {
+		t.Fatalf("Load(yaml): %v", err)
+	}
+	if got := r.Canonical("jdoe"); got != "Jane Doe" {
+		t.Errorf("Canonical(jdoe) = %q, want Jane Doe", got)
+	}
+
+	jsonPath := filepath.Join(dir, "identities.json")
+	jsonContent := `[{"id":"jdoe","displayName":"Jane Doe","githubLogins":["jdoe"],"emails":["jane@example.com"]}]`
+	if err := os.WriteFile(jsonPath, []byte(jsonContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = Load(jsonPath)
+	if err != nil {
+		t.Fatalf("Load(json): %v", err)
+	}
+	if got := r.Canonical("jdoe"); got != "Jane Doe" {
+		t.Errorf("Canonical(jdoe) = %q, want Jane Doe", got)
+	}
+}